@@ -0,0 +1,89 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Privado-Inc/privado-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var authTokenPrintClaims bool
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect privado's authentication state",
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print the on-disk user token",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := config.LoadUserToken()
+		if err != nil {
+			return fmt.Errorf("no user token found: %w", err)
+		}
+
+		if !authTokenPrintClaims {
+			fmt.Println(token)
+			return nil
+		}
+
+		claims, err := config.ClaimsFromToken()
+		if err != nil {
+			return err
+		}
+
+		encoded, err := json.MarshalIndent(claims, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(encoded))
+		return nil
+	},
+}
+
+var authKeypairCmd = &cobra.Command{
+	Use:   "keypair",
+	Short: "Print the user's Ed25519 public key, generating one first if needed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pub, _, err := config.EnsureUserKeypair()
+		if err != nil {
+			return fmt.Errorf("could not load or generate user keypair: %w", err)
+		}
+
+		fmt.Println(hex.EncodeToString(pub))
+		return nil
+	},
+}
+
+func init() {
+	authTokenCmd.Flags().BoolVar(&authTokenPrintClaims, "print-claims", false, "verify the token and print its decoded claims instead of the raw token")
+
+	authCmd.AddCommand(authTokenCmd)
+	authCmd.AddCommand(authKeypairCmd)
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,72 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Privado-Inc/privado-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cachePruneDryRun bool
+	cachePruneKind   string
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage privado's package cache mirrors",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Evict m2/gradle cache entries past their retention policy",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		result, err := config.PruneCache(repoRoot, cachePruneKind, cachePruneDryRun, flagLayeredConfig(cmd))
+		if err != nil {
+			return err
+		}
+
+		verb := "Removed"
+		if cachePruneDryRun {
+			verb = "Would remove"
+		}
+		fmt.Printf("%s %d file(s), reclaiming %d bytes\n", verb, result.FilesRemoved, result.BytesReclaimed)
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().BoolVar(&cachePruneDryRun, "dry-run", false, "report what would be removed without deleting anything")
+	cachePruneCmd.Flags().StringVar(&cachePruneKind, "kind", "all", "cache to prune: m2, gradle, or all")
+
+	cacheCmd.AddCommand(cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
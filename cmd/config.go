@@ -0,0 +1,62 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Privado-Inc/privado-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and manage privado's configuration",
+}
+
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the fully-merged effective configuration",
+	Long: "Resolves privado.conf from /etc/privado, the user config directory and the " +
+		"current repo's .privado directory, layers environment variables and CLI flags on " +
+		"top, and prints the resulting configuration so precedence issues are easy to debug.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		effective, err := config.LoadLayeredConfig(repoRoot, flagLayeredConfig(cmd))
+		if err != nil {
+			return err
+		}
+
+		return toml.NewEncoder(os.Stdout).Encode(effective)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configDumpCmd)
+}
@@ -0,0 +1,81 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package cmd
+
+import (
+	"github.com/Privado-Inc/privado-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// Persistent flags giving the top tier of config.LoadLayeredConfig's
+// precedence order (CLI flags override everything) something real to read
+// from. They're registered on rootCmd so they're available on every
+// subcommand, unlike e.g. --kind, which is only available on "cache prune".
+var (
+	flagImageURL          string
+	flagTelemetryEndpoint string
+	flagTelemetryEnabled  bool
+	flagSlowdownTime      string
+	flagContainerRuntime  string
+	flagCacheDir          string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flagImageURL, "image-url", "", "override the scan container image URL")
+	rootCmd.PersistentFlags().StringVar(&flagTelemetryEndpoint, "telemetry-endpoint", "", "override the telemetry endpoint")
+	rootCmd.PersistentFlags().BoolVar(&flagTelemetryEnabled, "telemetry-enabled", true, "enable or disable telemetry")
+	rootCmd.PersistentFlags().StringVar(&flagSlowdownTime, "slowdown-time", "", "override the engine slowdown duration, e.g. \"600ms\"")
+	rootCmd.PersistentFlags().StringVar(&flagContainerRuntime, "container-runtime", "", "force a specific container runtime (docker or podman)")
+	rootCmd.PersistentFlags().StringVar(&flagCacheDir, "cache-dir", "", "override the package cache directory")
+}
+
+// flagLayeredConfig builds the flagOverrides argument to
+// config.LoadLayeredConfig from whichever persistent flags the user
+// actually passed on cmd, so an unset flag never clobbers a lower-precedence
+// source with its zero value.
+func flagLayeredConfig(cmd *cobra.Command) config.LayeredConfig {
+	var overrides config.LayeredConfig
+
+	flags := cmd.Flags()
+	if flags.Changed("image-url") {
+		overrides.Engine.ImageURL = flagImageURL
+	}
+	if flags.Changed("telemetry-endpoint") {
+		overrides.Engine.TelemetryEndpoint = flagTelemetryEndpoint
+	}
+	if flags.Changed("telemetry-enabled") {
+		enabled := flagTelemetryEnabled
+		overrides.Engine.TelemetryEnabled = &enabled
+	}
+	if flags.Changed("slowdown-time") {
+		overrides.Engine.SlowdownTime = flagSlowdownTime
+	}
+	if flags.Changed("container-runtime") {
+		overrides.Containers.Runtime = flagContainerRuntime
+	}
+	if flags.Changed("cache-dir") {
+		overrides.Cache.CustomLocation = flagCacheDir
+	}
+
+	return overrides
+}
@@ -0,0 +1,62 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Privado-Inc/privado-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "privado",
+	Short: "Privado CLI - discover data flows in your code",
+	// resolve and apply the layered privado.conf/env/flag config, then
+	// opportunistically evict stale cache entries (a no-op unless the
+	// configured sweep interval has elapsed), before any subcommand runs
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if repoRoot, err := os.Getwd(); err == nil {
+			if effective, err := config.LoadLayeredConfig(repoRoot, flagLayeredConfig(cmd)); err == nil {
+				config.ApplyLayeredConfig(effective)
+			}
+		}
+
+		// "cache prune" is the user explicitly asking to evict cache
+		// entries themselves (optionally with --dry-run); don't race it
+		// with an opportunistic sweep that would delete for real first
+		if cmd.CommandPath() == "privado cache prune" {
+			return
+		}
+		config.RunOpportunisticSweep()
+	},
+}
+
+// Execute runs the root command.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -40,10 +40,13 @@ var AppConfig *Configuration
 type Configuration struct {
 	HomeDirectory                    string
 	CacheDirectory                   string
+	StateDirectory                   string
 	ConfigurationDirectory           string
 	UserConfigurationFilePath        string
 	UserKeyDirectory                 string
 	UserKeyPath                      string
+	UserTokenPath                    string
+	IsDev                            bool
 	CIUserIdentifierEnvKey           string
 	M2CacheDirectoryName             string
 	GradleCacheDirectoryName         string
@@ -58,8 +61,11 @@ type Configuration struct {
 }
 
 type ContainerConfiguration struct {
-	ImageURL                    string
-	DockerAccessKeyEnv          string
+	ImageURL           string
+	DockerAccessKeyEnv string
+	// Runtime selects the container engine ("docker" or "podman"); empty
+	// means auto-detect (see container.Detect).
+	Runtime                     string
 	UserKeyVolumeDir            string
 	DockerKeyVolumeDir          string
 	UserConfigVolumeDir         string
@@ -69,6 +75,10 @@ type ContainerConfiguration struct {
 	ExternalRulesVolumeDir      string
 	M2PackageCacheVolumeDir     string
 	GradlePackageCacheVolumeDir string
+	ExtraEnv                    []string
+	ExtraMounts                 []string
+	MemoryLimit                 string
+	CPULimit                    string
 	PrivadoCoreBinPath          string
 }
 
@@ -97,12 +107,17 @@ func init() {
 		}
 	}
 
+	configDir, cacheDir, dataDir, stateDir := resolveXDGDirectories(home)
+
 	AppConfig = &Configuration{
 		HomeDirectory:                    home,
-		ConfigurationDirectory:           filepath.Join(home, ".privado"),
-		UserConfigurationFilePath:        filepath.Join(home, ".privado", "config.json"),
-		UserKeyDirectory:                 filepath.Join(home, ".privado", "keys"),
-		UserKeyPath:                      filepath.Join(home, ".privado", "keys", "user.key"),
+		StateDirectory:                   stateDir,
+		ConfigurationDirectory:           configDir,
+		UserConfigurationFilePath:        filepath.Join(configDir, "config.json"),
+		UserKeyDirectory:                 filepath.Join(dataDir, "keys"),
+		UserKeyPath:                      filepath.Join(dataDir, "keys", "user.key"),
+		UserTokenPath:                    filepath.Join(dataDir, "keys", "user.token"),
+		IsDev:                            isDev,
 		CIUserIdentifierEnvKey:           "PRIVADO_CI_USER_ID",
 		M2CacheDirectoryName:             ".m2",
 		GradleCacheDirectoryName:         ".gradle",
@@ -129,52 +144,84 @@ func init() {
 		},
 	}
 
-	privadoCacheDir, _ := initPrivadoCacheDirectory()
+	migrateLegacyConfiguration(home, cacheDir)
+
+	privadoCacheDir, _ := initPrivadoCacheDirectory(cacheDir)
 	AppConfig.CacheDirectory = privadoCacheDir
 }
 
-// returns existing privado cache directory
-// if not available - creates one and returns
-func initPrivadoCacheDirectory() (string, error) {
-	cacheDir := getPrivadoCacheDirectory()
-	if cacheDir != "" {
-		return cacheDir, nil
+// xdgBaseDir resolves the base directory for the given XDG environment
+// variable, falling back to the standard $HOME-relative default when unset
+// or not an absolute path, per the XDG Base Directory Specification.
+func xdgBaseDir(home, envKey, fallback string) string {
+	if v := os.Getenv(envKey); filepath.IsAbs(v) {
+		return v
 	}
-	return createPrivadoCacheDirectory()
+	return filepath.Join(home, fallback)
 }
 
-func createPrivadoCacheDirectory() (string, error) {
-	if systemDefinedCacheDir, err := os.UserCacheDir(); err != nil {
-		location := filepath.Join(AppConfig.ConfigurationDirectory, ".cache")
-		if err := os.MkdirAll(location, os.ModePerm); err != nil {
-			return "", err
-		}
-		return location, nil
-	} else {
-		location := filepath.Join(systemDefinedCacheDir, "privado")
-		if err := os.MkdirAll(location, os.ModePerm); err != nil {
-			return "", err
-		}
-		return location, nil
+// resolveXDGDirectories computes the config, cache, data and state
+// directories privado should use. If PRIVADO_HOME is set, all four collapse
+// into a single directory (mirroring the legacy ~/.privado layout), which is
+// convenient for CI runs and containers that only want to mount one path.
+func resolveXDGDirectories(home string) (configDir, cacheDir, dataDir, stateDir string) {
+	if privadoHome := os.Getenv("PRIVADO_HOME"); privadoHome != "" {
+		return privadoHome, privadoHome, privadoHome, privadoHome
 	}
+
+	configHome := xdgBaseDir(home, "XDG_CONFIG_HOME", ".config")
+	cacheHome := xdgBaseDir(home, "XDG_CACHE_HOME", ".cache")
+	dataHome := xdgBaseDir(home, "XDG_DATA_HOME", filepath.Join(".local", "share"))
+	stateHome := xdgBaseDir(home, "XDG_STATE_HOME", filepath.Join(".local", "state"))
+
+	return filepath.Join(configHome, "privado"),
+		filepath.Join(cacheHome, "privado"),
+		filepath.Join(dataHome, "privado"),
+		filepath.Join(stateHome, "privado")
 }
 
-// Opposite direction from create - check if fallbacks are created first
-// then going forward, continue to use them instead of creating other dir
-func getPrivadoCacheDirectory() string {
-	location := filepath.Join(AppConfig.ConfigurationDirectory, ".cache")
-	if exists, _ := fileutils.DoesFileExists(location); exists {
-		return location
+// migrateLegacyConfiguration moves config.json, keys/ and the package cache
+// mirrors out of the pre-XDG ~/.privado directory into their new XDG
+// locations the first time the CLI runs after upgrading. It is a no-op once
+// the legacy directory is gone, and is skipped entirely under PRIVADO_HOME
+// since that already collapses everything back into one directory.
+func migrateLegacyConfiguration(home, cacheDir string) {
+	if os.Getenv("PRIVADO_HOME") != "" {
+		return
+	}
+
+	legacyDir := filepath.Join(home, ".privado")
+	if exists, _ := fileutils.DoesFileExists(legacyDir); !exists {
+		return
+	}
+
+	moves := map[string]string{
+		filepath.Join(legacyDir, "config.json"): AppConfig.UserConfigurationFilePath,
+		filepath.Join(legacyDir, "keys"):        AppConfig.UserKeyDirectory,
+		filepath.Join(legacyDir, ".cache"):      cacheDir,
 	}
 
-	if systemDefinedCacheDir, err := os.UserCacheDir(); err == nil {
-		location := filepath.Join(systemDefinedCacheDir, "privado")
-		if exists, _ := fileutils.DoesFileExists(location); exists {
-			return location
+	for oldPath, newPath := range moves {
+		if exists, _ := fileutils.DoesFileExists(oldPath); !exists {
+			continue
 		}
+		if exists, _ := fileutils.DoesFileExists(newPath); exists {
+			// new location already populated, don't clobber it
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(newPath), os.ModePerm); err != nil {
+			continue
+		}
+		_ = os.Rename(oldPath, newPath)
 	}
+}
 
-	return ""
+// returns the resolved privado cache directory, creating it if necessary
+func initPrivadoCacheDirectory(cacheDir string) (string, error) {
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	return cacheDir, nil
 }
 
 func GetPackageCacheDirectory(packageManager string) (string, error) {
@@ -208,8 +255,8 @@ func GetPackageCacheDirectory(packageManager string) (string, error) {
 		// if default location does not exist, create dir in PrivadoCache and use that one
 		// if cacheDir is empty, try creating again
 		if cacheDir == "" {
-			cacheDir, err = createPrivadoCacheDirectory()
-			if err != nil {
+			_, cacheDir, _, _ = resolveXDGDirectories(home)
+			if _, err := initPrivadoCacheDirectory(cacheDir); err != nil {
 				return "", err
 			}
 		}
@@ -0,0 +1,291 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Privado-Inc/privado-cli/pkg/fileutils"
+)
+
+// CacheRetentionPolicy is the parsed, ready-to-use form of [CacheConfig]'s
+// retention knobs.
+type CacheRetentionPolicy struct {
+	ReleasedMaxAge time.Duration
+	SnapshotMaxAge time.Duration
+	MaxTotalSize   int64 // bytes, 0 means no size ceiling
+	SweepInterval  time.Duration
+}
+
+// LoadCacheRetentionPolicy parses the retention fields of a CacheConfig into
+// a CacheRetentionPolicy, falling back to the compiled-in defaults for any
+// field that fails to parse or is empty.
+func LoadCacheRetentionPolicy(cfg CacheConfig) CacheRetentionPolicy {
+	defaults := CacheRetentionPolicy{
+		ReleasedMaxAge: 30 * 24 * time.Hour,
+		SnapshotMaxAge: 7 * 24 * time.Hour,
+		MaxTotalSize:   5 * 1024 * 1024 * 1024, // 5 GiB
+		SweepInterval:  24 * time.Hour,
+	}
+
+	if d, err := time.ParseDuration(cfg.ReleasedMaxAge); err == nil {
+		defaults.ReleasedMaxAge = d
+	}
+	if d, err := time.ParseDuration(cfg.SnapshotMaxAge); err == nil {
+		defaults.SnapshotMaxAge = d
+	}
+	if b, err := parseByteSize(cfg.MaxTotalSize); err == nil {
+		defaults.MaxTotalSize = b
+	}
+	if d, err := time.ParseDuration(cfg.SweepInterval); err == nil {
+		defaults.SweepInterval = d
+	}
+
+	return defaults
+}
+
+// parseByteSize parses sizes like "5GiB", "512MB", "100000" (bytes).
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+		{"GB", 1_000_000_000}, {"MB", 1_000_000}, {"KB", 1_000},
+		{"B", 1},
+	}
+
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				return 0, err
+			}
+			return int64(value * float64(u.multiplier)), nil
+		}
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// CachePruneResult reports what a prune pass removed (or would remove, in
+// dry-run mode).
+type CachePruneResult struct {
+	FilesRemoved   int
+	BytesReclaimed int64
+}
+
+// privadoCacheMirrorDir returns the path to privado's own cache mirror for
+// the given kind ("m2" or "gradle") and whether it currently exists.
+// Retention must only ever evict from privado's own mirror under
+// AppConfig.CacheDirectory - it must NEVER fall back to the shared system
+// package cache (~/.m2, ~/.gradle) the way GetPackageCacheDirectory does,
+// since that is the user's real, non-privado build cache.
+func privadoCacheMirrorDir(kind string) (string, bool) {
+	if AppConfig.CacheDirectory == "" {
+		return "", false
+	}
+
+	dirName := AppConfig.GradleCacheDirectoryName
+	if kind == "m2" {
+		dirName = AppConfig.M2CacheDirectoryName
+	}
+
+	dir := filepath.Join(AppConfig.CacheDirectory, dirName)
+	exists, _ := fileutils.DoesFileExists(dir)
+	return dir, exists
+}
+
+// isSnapshotArtifact is a cheap heuristic for distinguishing released
+// artifacts from snapshot ones across both Maven's ".m2" and Gradle's
+// ".gradle" caches - both embed "SNAPSHOT" in the file/module name for
+// non-released artifacts.
+func isSnapshotArtifact(name string) bool {
+	return strings.Contains(name, "SNAPSHOT")
+}
+
+// PruneCache evicts cache entries under the given package-manager kind
+// ("m2", "gradle", or "all") that are older than their kind's configured
+// max age, then evicts the least-recently-modified remaining entries if the
+// cache is still over its configured total size ceiling. In dry-run mode,
+// nothing is deleted; the result reports what would have been reclaimed.
+// The retention policy is the fully-merged config for repoRoot - system and
+// user privado.conf, a per-repo .privado/privado.conf, env vars, then
+// flagOverrides.
+func PruneCache(repoRoot, kind string, dryRun bool, flagOverrides LayeredConfig) (CachePruneResult, error) {
+	kinds := []string{"m2", "gradle"}
+	if kind != "" && kind != "all" {
+		kinds = []string{kind}
+	}
+
+	effective, err := LoadLayeredConfig(repoRoot, flagOverrides)
+	if err != nil {
+		return CachePruneResult{}, err
+	}
+	policy := LoadCacheRetentionPolicy(effective.Cache)
+
+	var result CachePruneResult
+	now := time.Now()
+
+	for _, k := range kinds {
+		dir, exists := privadoCacheMirrorDir(k)
+		if !exists {
+			// privado has never mirrored this cache kind on this host -
+			// nothing of ours to evict, and we must not touch the
+			// shared system ~/.m2 or ~/.gradle instead
+			continue
+		}
+
+		maxAge := policy.ReleasedMaxAge
+
+		var entries []string
+		var kept int64
+
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+
+			age := now.Sub(info.ModTime())
+			entryMaxAge := maxAge
+			if isSnapshotArtifact(info.Name()) {
+				entryMaxAge = policy.SnapshotMaxAge
+			}
+
+			if age > entryMaxAge {
+				if !dryRun {
+					if err := os.Remove(path); err != nil {
+						return err
+					}
+				}
+				result.FilesRemoved++
+				result.BytesReclaimed += info.Size()
+				return nil
+			}
+
+			entries = append(entries, path)
+			kept += info.Size()
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+
+		if policy.MaxTotalSize > 0 && kept > policy.MaxTotalSize {
+			if err := evictLRUUntilUnderSize(entries, kept, policy.MaxTotalSize, dryRun, &result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// evictLRUUntilUnderSize removes the least-recently-modified files in
+// entries until the running total drops to maxSize or below.
+func evictLRUUntilUnderSize(entries []string, total, maxSize int64, dryRun bool, result *CachePruneResult) error {
+	type fileEntry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []fileEntry
+	for _, path := range entries {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		files = append(files, fileEntry{path, info.Size(), info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		if total <= maxSize {
+			break
+		}
+		if !dryRun {
+			if err := os.Remove(f.path); err != nil {
+				return err
+			}
+		}
+		result.FilesRemoved++
+		result.BytesReclaimed += f.size
+		total -= f.size
+	}
+
+	return nil
+}
+
+// sweepStampPath is the file used to remember when the opportunistic
+// cleanup last ran, so it fires at most once per configured SweepInterval.
+func sweepStampPath() string {
+	return filepath.Join(AppConfig.StateDirectory, "cache-sweep.stamp")
+}
+
+// RunOpportunisticSweep runs PruneCache(cwd, "all", false, LayeredConfig{})
+// at most once per
+// policy.SweepInterval, tracked via a stamp file under the state
+// directory. It is meant to be called once at CLI startup; errors are
+// non-fatal since a missed sweep just means the cache stays a bit larger.
+func RunOpportunisticSweep() {
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	effective, err := LoadLayeredConfig(repoRoot, LayeredConfig{})
+	if err != nil {
+		return
+	}
+	policy := LoadCacheRetentionPolicy(effective.Cache)
+
+	stamp := sweepStampPath()
+	if info, err := os.Stat(stamp); err == nil {
+		if time.Since(info.ModTime()) < policy.SweepInterval {
+			return
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(stamp), os.ModePerm); err != nil {
+		return
+	}
+
+	_, _ = PruneCache(repoRoot, "all", false, LayeredConfig{})
+
+	_ = os.WriteFile(stamp, []byte(time.Now().UTC().Format(time.RFC3339)), 0644)
+}
@@ -0,0 +1,43 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package config
+
+import "github.com/Privado-Inc/privado-cli/pkg/container"
+
+// RunConfig builds the container.RunConfig scan orchestration should hand
+// to container.Runtime.Run, carrying over the user's extra env/mounts and
+// resource limits from the [containers] config alongside the mounts, env
+// and command passed in. The *VolumeDir fields are not turned into mounts
+// here; the caller builds those into mounts itself.
+func (c *ContainerConfiguration) RunConfig(mounts []container.Mount, env map[string]string, command []string) container.RunConfig {
+	return container.RunConfig{
+		ImageURL:    c.ImageURL,
+		Mounts:      mounts,
+		Env:         env,
+		Command:     command,
+		ExtraMounts: c.ExtraMounts,
+		ExtraEnv:    c.ExtraEnv,
+		MemoryLimit: c.MemoryLimit,
+		CPULimit:    c.CPULimit,
+	}
+}
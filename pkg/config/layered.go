@@ -0,0 +1,310 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Privado-Inc/privado-cli/pkg/fileutils"
+)
+
+// LayeredConfig is the TOML-shaped, user-overridable counterpart to
+// Configuration/ContainerConfiguration. It is merged field-by-field from,
+// in increasing order of precedence: compiled-in defaults,
+// /etc/privado/privado.conf, $XDG_CONFIG_HOME/privado/privado.conf,
+// a per-repo .privado/privado.conf, environment variables, and finally
+// CLI flags.
+type LayeredConfig struct {
+	Engine     EngineConfig     `toml:"engine"`
+	Containers ContainersConfig `toml:"containers"`
+	Cache      CacheConfig      `toml:"cache"`
+}
+
+type EngineConfig struct {
+	ImageURL          string `toml:"image_url"`
+	TelemetryEndpoint string `toml:"telemetry_endpoint"`
+	TelemetryEnabled  *bool  `toml:"telemetry_enabled"`
+	// SlowdownTime is a duration string (e.g. "600ms"), like the cache
+	// retention knobs below - not a time.Duration, since BurntSushi/toml
+	// would otherwise decode it as a raw integer of nanoseconds.
+	SlowdownTime string `toml:"slowdown_time"`
+}
+
+type ContainersConfig struct {
+	// Runtime selects the container engine ("docker" or "podman"). Empty
+	// means auto-detect; see container.Detect.
+	Runtime                     string   `toml:"runtime"`
+	UserKeyVolumeDir            string   `toml:"user_key_volume_dir"`
+	DockerKeyVolumeDir          string   `toml:"docker_key_volume_dir"`
+	UserConfigVolumeDir         string   `toml:"user_config_volume_dir"`
+	LogConfigVolumeDir          string   `toml:"log_config_volume_dir"`
+	SourceCodeVolumeDir         string   `toml:"source_code_volume_dir"`
+	InternalRulesVolumeDir      string   `toml:"internal_rules_volume_dir"`
+	ExternalRulesVolumeDir      string   `toml:"external_rules_volume_dir"`
+	M2PackageCacheVolumeDir     string   `toml:"m2_package_cache_volume_dir"`
+	GradlePackageCacheVolumeDir string   `toml:"gradle_package_cache_volume_dir"`
+	ExtraEnv                    []string `toml:"extra_env"`
+	ExtraMounts                 []string `toml:"extra_mounts"`
+	MemoryLimit                 string   `toml:"memory_limit"`
+	CPULimit                    string   `toml:"cpu_limit"`
+}
+
+type CacheConfig struct {
+	M2DirectoryName     string `toml:"m2_directory_name"`
+	GradleDirectoryName string `toml:"gradle_directory_name"`
+	CustomLocation      string `toml:"custom_location"`
+
+	ReleasedMaxAge string `toml:"released_max_age"`
+	SnapshotMaxAge string `toml:"snapshot_max_age"`
+	MaxTotalSize   string `toml:"max_total_size"`
+	SweepInterval  string `toml:"sweep_interval"`
+}
+
+// SystemConfigPath, UserConfigPath and RepoConfigPath return the locations
+// privado.conf is looked for, in precedence order (later overrides earlier).
+func SystemConfigPath() string {
+	return filepath.Join("/etc", "privado", "privado.conf")
+}
+
+func UserConfigPath() string {
+	return filepath.Join(AppConfig.ConfigurationDirectory, "privado.conf")
+}
+
+func RepoConfigPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".privado", "privado.conf")
+}
+
+// defaultLayeredConfig mirrors the compiled-in defaults from AppConfig, so
+// that a tree with no privado.conf anywhere behaves exactly as before.
+func defaultLayeredConfig() LayeredConfig {
+	telemetryEnabled := true
+	return LayeredConfig{
+		Engine: EngineConfig{
+			ImageURL:          AppConfig.Container.ImageURL,
+			TelemetryEndpoint: AppConfig.PrivadoTelemetryEndpoint,
+			TelemetryEnabled:  &telemetryEnabled,
+			SlowdownTime:      AppConfig.SlowdownTime.String(),
+		},
+		Containers: ContainersConfig{
+			UserKeyVolumeDir:            AppConfig.Container.UserKeyVolumeDir,
+			DockerKeyVolumeDir:          AppConfig.Container.DockerKeyVolumeDir,
+			UserConfigVolumeDir:         AppConfig.Container.UserConfigVolumeDir,
+			LogConfigVolumeDir:          AppConfig.Container.LogConfigVolumeDir,
+			SourceCodeVolumeDir:         AppConfig.Container.SourceCodeVolumeDir,
+			InternalRulesVolumeDir:      AppConfig.Container.InternalRulesVolumeDir,
+			ExternalRulesVolumeDir:      AppConfig.Container.ExternalRulesVolumeDir,
+			M2PackageCacheVolumeDir:     AppConfig.Container.M2PackageCacheVolumeDir,
+			GradlePackageCacheVolumeDir: AppConfig.Container.GradlePackageCacheVolumeDir,
+		},
+		Cache: CacheConfig{
+			M2DirectoryName:     AppConfig.M2CacheDirectoryName,
+			GradleDirectoryName: AppConfig.GradleCacheDirectoryName,
+			ReleasedMaxAge:      "720h", // 30 days
+			SnapshotMaxAge:      "168h", // 7 days
+			MaxTotalSize:        "5GiB",
+			SweepInterval:       "24h",
+		},
+	}
+}
+
+// LoadLayeredConfig resolves the effective configuration for repoRoot by
+// merging, in order, the compiled-in defaults, the system config, the user
+// config, the per-repo config, environment variables and finally
+// flagOverrides. Each source only overrides fields it actually sets.
+func LoadLayeredConfig(repoRoot string, flagOverrides LayeredConfig) (LayeredConfig, error) {
+	effective := defaultLayeredConfig()
+
+	for _, path := range []string{SystemConfigPath(), UserConfigPath(), RepoConfigPath(repoRoot)} {
+		if exists, _ := fileutils.DoesFileExists(path); !exists {
+			continue
+		}
+		var layer LayeredConfig
+		if _, err := toml.DecodeFile(path, &layer); err != nil {
+			return LayeredConfig{}, err
+		}
+		effective = mergeLayeredConfig(effective, layer)
+	}
+
+	effective = mergeLayeredConfig(effective, layeredConfigFromEnv())
+	effective = mergeLayeredConfig(effective, flagOverrides)
+
+	return effective, nil
+}
+
+// layeredConfigFromEnv reads the subset of knobs that are also exposed as
+// environment variables, for use in CI/containers where dropping a
+// privado.conf file is less convenient than setting env vars.
+func layeredConfigFromEnv() LayeredConfig {
+	var env LayeredConfig
+
+	env.Engine.ImageURL = os.Getenv("PRIVADO_ENGINE_IMAGE_URL")
+	env.Engine.TelemetryEndpoint = os.Getenv("PRIVADO_ENGINE_TELEMETRY_ENDPOINT")
+	if v, err := strconv.ParseBool(os.Getenv("PRIVADO_ENGINE_TELEMETRY_ENABLED")); err == nil {
+		env.Engine.TelemetryEnabled = &v
+	}
+	env.Engine.SlowdownTime = os.Getenv("PRIVADO_ENGINE_SLOWDOWN_TIME")
+
+	env.Containers.Runtime = os.Getenv("PRIVADO_CONTAINER_RUNTIME")
+	env.Containers.MemoryLimit = os.Getenv("PRIVADO_CONTAINERS_MEMORY_LIMIT")
+	env.Containers.CPULimit = os.Getenv("PRIVADO_CONTAINERS_CPU_LIMIT")
+	if extraEnv := os.Getenv("PRIVADO_CONTAINERS_EXTRA_ENV"); extraEnv != "" {
+		env.Containers.ExtraEnv = strings.Split(extraEnv, ",")
+	}
+	if extraMounts := os.Getenv("PRIVADO_CONTAINERS_EXTRA_MOUNTS"); extraMounts != "" {
+		env.Containers.ExtraMounts = strings.Split(extraMounts, ",")
+	}
+
+	env.Cache.M2DirectoryName = os.Getenv("PRIVADO_CACHE_M2_DIRECTORY_NAME")
+	env.Cache.GradleDirectoryName = os.Getenv("PRIVADO_CACHE_GRADLE_DIRECTORY_NAME")
+	env.Cache.CustomLocation = os.Getenv("PRIVADO_CACHE_CUSTOM_LOCATION")
+	env.Cache.ReleasedMaxAge = os.Getenv("PRIVADO_CACHE_RELEASED_MAX_AGE")
+	env.Cache.SnapshotMaxAge = os.Getenv("PRIVADO_CACHE_SNAPSHOT_MAX_AGE")
+	env.Cache.MaxTotalSize = os.Getenv("PRIVADO_CACHE_MAX_TOTAL_SIZE")
+	env.Cache.SweepInterval = os.Getenv("PRIVADO_CACHE_SWEEP_INTERVAL")
+
+	return env
+}
+
+// mergeLayeredConfig overlays the non-zero fields of overlay onto base,
+// field-by-field, and returns the result. base is left untouched.
+func mergeLayeredConfig(base, overlay LayeredConfig) LayeredConfig {
+	if overlay.Engine.ImageURL != "" {
+		base.Engine.ImageURL = overlay.Engine.ImageURL
+	}
+	if overlay.Engine.TelemetryEndpoint != "" {
+		base.Engine.TelemetryEndpoint = overlay.Engine.TelemetryEndpoint
+	}
+	if overlay.Engine.TelemetryEnabled != nil {
+		base.Engine.TelemetryEnabled = overlay.Engine.TelemetryEnabled
+	}
+	if overlay.Engine.SlowdownTime != "" {
+		base.Engine.SlowdownTime = overlay.Engine.SlowdownTime
+	}
+
+	if overlay.Containers.Runtime != "" {
+		base.Containers.Runtime = overlay.Containers.Runtime
+	}
+	if overlay.Containers.UserKeyVolumeDir != "" {
+		base.Containers.UserKeyVolumeDir = overlay.Containers.UserKeyVolumeDir
+	}
+	if overlay.Containers.DockerKeyVolumeDir != "" {
+		base.Containers.DockerKeyVolumeDir = overlay.Containers.DockerKeyVolumeDir
+	}
+	if overlay.Containers.UserConfigVolumeDir != "" {
+		base.Containers.UserConfigVolumeDir = overlay.Containers.UserConfigVolumeDir
+	}
+	if overlay.Containers.LogConfigVolumeDir != "" {
+		base.Containers.LogConfigVolumeDir = overlay.Containers.LogConfigVolumeDir
+	}
+	if overlay.Containers.SourceCodeVolumeDir != "" {
+		base.Containers.SourceCodeVolumeDir = overlay.Containers.SourceCodeVolumeDir
+	}
+	if overlay.Containers.InternalRulesVolumeDir != "" {
+		base.Containers.InternalRulesVolumeDir = overlay.Containers.InternalRulesVolumeDir
+	}
+	if overlay.Containers.ExternalRulesVolumeDir != "" {
+		base.Containers.ExternalRulesVolumeDir = overlay.Containers.ExternalRulesVolumeDir
+	}
+	if overlay.Containers.M2PackageCacheVolumeDir != "" {
+		base.Containers.M2PackageCacheVolumeDir = overlay.Containers.M2PackageCacheVolumeDir
+	}
+	if overlay.Containers.GradlePackageCacheVolumeDir != "" {
+		base.Containers.GradlePackageCacheVolumeDir = overlay.Containers.GradlePackageCacheVolumeDir
+	}
+	if overlay.Containers.ExtraEnv != nil {
+		base.Containers.ExtraEnv = overlay.Containers.ExtraEnv
+	}
+	if overlay.Containers.ExtraMounts != nil {
+		base.Containers.ExtraMounts = overlay.Containers.ExtraMounts
+	}
+	if overlay.Containers.MemoryLimit != "" {
+		base.Containers.MemoryLimit = overlay.Containers.MemoryLimit
+	}
+	if overlay.Containers.CPULimit != "" {
+		base.Containers.CPULimit = overlay.Containers.CPULimit
+	}
+
+	if overlay.Cache.M2DirectoryName != "" {
+		base.Cache.M2DirectoryName = overlay.Cache.M2DirectoryName
+	}
+	if overlay.Cache.GradleDirectoryName != "" {
+		base.Cache.GradleDirectoryName = overlay.Cache.GradleDirectoryName
+	}
+	if overlay.Cache.CustomLocation != "" {
+		base.Cache.CustomLocation = overlay.Cache.CustomLocation
+	}
+	if overlay.Cache.ReleasedMaxAge != "" {
+		base.Cache.ReleasedMaxAge = overlay.Cache.ReleasedMaxAge
+	}
+	if overlay.Cache.SnapshotMaxAge != "" {
+		base.Cache.SnapshotMaxAge = overlay.Cache.SnapshotMaxAge
+	}
+	if overlay.Cache.MaxTotalSize != "" {
+		base.Cache.MaxTotalSize = overlay.Cache.MaxTotalSize
+	}
+	if overlay.Cache.SweepInterval != "" {
+		base.Cache.SweepInterval = overlay.Cache.SweepInterval
+	}
+
+	return base
+}
+
+// ApplyLayeredConfig writes the effective, merged configuration back onto
+// AppConfig so the rest of the CLI keeps reading from the single
+// Configuration/ContainerConfiguration structs it already knows about.
+// Engine.TelemetryEnabled has no counterpart on Configuration and is not
+// written back, so it only shows up in the merged config itself.
+func ApplyLayeredConfig(cfg LayeredConfig) {
+	AppConfig.Container.ImageURL = cfg.Engine.ImageURL
+	AppConfig.PrivadoTelemetryEndpoint = cfg.Engine.TelemetryEndpoint
+	if d, err := time.ParseDuration(cfg.Engine.SlowdownTime); err == nil {
+		AppConfig.SlowdownTime = d
+	}
+
+	AppConfig.Container.Runtime = cfg.Containers.Runtime
+	AppConfig.Container.UserKeyVolumeDir = cfg.Containers.UserKeyVolumeDir
+	AppConfig.Container.DockerKeyVolumeDir = cfg.Containers.DockerKeyVolumeDir
+	AppConfig.Container.UserConfigVolumeDir = cfg.Containers.UserConfigVolumeDir
+	AppConfig.Container.LogConfigVolumeDir = cfg.Containers.LogConfigVolumeDir
+	AppConfig.Container.SourceCodeVolumeDir = cfg.Containers.SourceCodeVolumeDir
+	AppConfig.Container.InternalRulesVolumeDir = cfg.Containers.InternalRulesVolumeDir
+	AppConfig.Container.ExternalRulesVolumeDir = cfg.Containers.ExternalRulesVolumeDir
+	AppConfig.Container.M2PackageCacheVolumeDir = cfg.Containers.M2PackageCacheVolumeDir
+	AppConfig.Container.GradlePackageCacheVolumeDir = cfg.Containers.GradlePackageCacheVolumeDir
+	AppConfig.Container.ExtraEnv = cfg.Containers.ExtraEnv
+	AppConfig.Container.ExtraMounts = cfg.Containers.ExtraMounts
+	AppConfig.Container.MemoryLimit = cfg.Containers.MemoryLimit
+	AppConfig.Container.CPULimit = cfg.Containers.CPULimit
+
+	AppConfig.M2CacheDirectoryName = cfg.Cache.M2DirectoryName
+	AppConfig.GradleCacheDirectoryName = cfg.Cache.GradleDirectoryName
+	if cfg.Cache.CustomLocation != "" {
+		AppConfig.CacheDirectory = cfg.Cache.CustomLocation
+		_ = os.MkdirAll(AppConfig.CacheDirectory, os.ModePerm)
+	}
+}
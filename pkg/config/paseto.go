@@ -0,0 +1,157 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+// UserTokenClaims are the claims carried by a Privado-issued v2.public
+// PASETO, identifying the user and any entitlements the server has granted
+// them (e.g. which scan image digest they're pinned to).
+type UserTokenClaims struct {
+	Subject        string    `json:"sub"`
+	IssuedAt       time.Time `json:"iat"`
+	ExpiresAt      time.Time `json:"exp"`
+	Scope          string    `json:"scope"`
+	ImageDigestPin string    `json:"image_digest_pin"`
+}
+
+// privadoRootPublicKeyHex is the hex-encoded Ed25519 public key used to
+// verify tokens issued by Privado's servers in production.
+//
+// TODO(release): replace with the real root public key before cutting a
+// release build; until then, production token verification will
+// correctly fail closed.
+const privadoRootPublicKeyHex = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// RootPublicKey returns the Ed25519 public key used to verify
+// Privado-issued tokens. Under PRIVADO_DEV, PRIVADO_DEV_PUBLIC_KEY (hex) is
+// used instead, so developers can verify tokens signed by a local/staging
+// issuer without the production root key.
+func RootPublicKey() (ed25519.PublicKey, error) {
+	keyHex := privadoRootPublicKeyHex
+	if AppConfig.IsDev {
+		if devKey := os.Getenv("PRIVADO_DEV_PUBLIC_KEY"); devKey != "" {
+			keyHex = devKey
+		}
+	}
+
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("malformed root public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("root public key has wrong size: got %d, want %d", len(key), ed25519.PublicKeySize)
+	}
+	// the all-zero placeholder decodes to a small-order point that ed25519
+	// verification accepts, so refuse it outright
+	if ed25519.PublicKey(key).Equal(ed25519.PublicKey(make([]byte, ed25519.PublicKeySize))) {
+		return nil, fmt.Errorf("no root public key is configured in this build")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// EnsureUserKeypair returns the user's Ed25519 keypair, generating and
+// persisting one under UserKeyDirectory (private key mode 0600) if it
+// doesn't exist yet.
+func EnsureUserKeypair() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	if existing, err := os.ReadFile(AppConfig.UserKeyPath); err == nil {
+		if len(existing) != ed25519.PrivateKeySize {
+			return nil, nil, fmt.Errorf("user key at %s is corrupt", AppConfig.UserKeyPath)
+		}
+		priv := ed25519.PrivateKey(existing)
+		return priv.Public().(ed25519.PublicKey), priv, nil
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := os.MkdirAll(AppConfig.UserKeyDirectory, os.ModePerm); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(AppConfig.UserKeyPath, priv, 0600); err != nil {
+		return nil, nil, err
+	}
+
+	return pub, priv, nil
+}
+
+// LoadUserToken reads the raw v2.public PASETO stored at UserTokenPath.
+func LoadUserToken() (string, error) {
+	data, err := os.ReadFile(AppConfig.UserTokenPath)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// VerifyToken verifies a v2.public PASETO against publicKey and decodes its
+// claims, rejecting tokens that are missing the exp claim or have expired.
+func VerifyToken(token string, publicKey ed25519.PublicKey) (*UserTokenClaims, error) {
+	var payload []byte
+	var footer []byte
+	if err := paseto.NewV2().Verify(token, publicKey, &payload, &footer); err != nil {
+		return nil, fmt.Errorf("token verification failed: %w", err)
+	}
+
+	var claims UserTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	if claims.ExpiresAt.IsZero() {
+		return nil, fmt.Errorf("token is missing the required exp claim")
+	}
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, fmt.Errorf("token expired at %s", claims.ExpiresAt)
+	}
+
+	return &claims, nil
+}
+
+// ClaimsFromToken loads the on-disk user token and verifies it against the
+// root public key, returning its typed claims.
+func ClaimsFromToken() (*UserTokenClaims, error) {
+	token, err := LoadUserToken()
+	if err != nil {
+		return nil, err
+	}
+
+	rootKey, err := RootPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return VerifyToken(token, rootKey)
+}
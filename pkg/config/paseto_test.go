@@ -0,0 +1,127 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+// signClaims mints a v2.public PASETO for claims under a freshly generated
+// keypair, returning the token and the public key it can be verified with.
+func signClaims(t *testing.T, claims UserTokenClaims) (string, ed25519.PublicKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("failed to marshal claims: %v", err)
+	}
+
+	token, err := paseto.NewV2().Sign(priv, payload, nil)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return token, pub
+}
+
+func TestVerifyToken_RejectsMissingExpClaim(t *testing.T) {
+	token, pub := signClaims(t, UserTokenClaims{Subject: "user-1", IssuedAt: time.Now()})
+
+	if _, err := VerifyToken(token, pub); err == nil {
+		t.Fatal("expected an error for a token missing the exp claim")
+	}
+}
+
+func TestVerifyToken_RejectsExpiredToken(t *testing.T) {
+	token, pub := signClaims(t, UserTokenClaims{
+		Subject:   "user-1",
+		IssuedAt:  time.Now().Add(-2 * time.Hour),
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+
+	if _, err := VerifyToken(token, pub); err == nil {
+		t.Fatal("expected an error for an expired token")
+	}
+}
+
+func TestVerifyToken_AcceptsValidToken(t *testing.T) {
+	want := UserTokenClaims{
+		Subject:   "user-1",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Scope:     "scan",
+	}
+	token, pub := signClaims(t, want)
+
+	claims, err := VerifyToken(token, pub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Subject != want.Subject || claims.Scope != want.Scope {
+		t.Fatalf("got claims %+v, want %+v", claims, want)
+	}
+}
+
+func TestEnsureUserKeypair_GeneratesAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	orig := AppConfig
+	AppConfig = &Configuration{
+		UserKeyDirectory: dir,
+		UserKeyPath:      filepath.Join(dir, "user.key"),
+	}
+	t.Cleanup(func() { AppConfig = orig })
+
+	pub1, priv1, err := EnsureUserKeypair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(AppConfig.UserKeyPath)
+	if err != nil {
+		t.Fatalf("expected key file to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("got key file mode %v, want 0600", info.Mode().Perm())
+	}
+
+	pub2, priv2, err := EnsureUserKeypair()
+	if err != nil {
+		t.Fatalf("unexpected error on second call: %v", err)
+	}
+	if !pub1.Equal(pub2) || string(priv1) != string(priv2) {
+		t.Fatal("expected the second call to reuse the persisted keypair rather than generating a new one")
+	}
+}
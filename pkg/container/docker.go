@@ -0,0 +1,67 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package container
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Docker runs scans via the `docker` CLI.
+type Docker struct{}
+
+func (d *Docker) Name() string { return "docker" }
+
+func (d *Docker) Available() bool {
+	_, err := lookPath("docker")
+	return err == nil
+}
+
+func (d *Docker) Run(cfg RunConfig) (string, error) {
+	args := []string{"run", "--rm"}
+
+	for _, m := range cfg.Mounts {
+		args = append(args, "-v", volumeArg(m))
+	}
+	for k, v := range cfg.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	args = append(args, resourceAndExtraArgs(cfg)...)
+
+	args = append(args, cfg.ImageURL)
+	args = append(args, cfg.Command...)
+
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	return string(out), err
+}
+
+// volumeArg renders a Mount as a `-v` argument. Docker doesn't understand
+// SELinux ":Z" relabeling, so m.Relabel is only honored by the Podman
+// backend.
+func volumeArg(m Mount) string {
+	flag := ""
+	if m.ReadOnly {
+		flag = ":ro"
+	}
+	return fmt.Sprintf("%s:%s%s", m.Source, m.Target, flag)
+}
@@ -0,0 +1,49 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package container
+
+// Fake is a Runtime that records every Run call instead of shelling out,
+// so scan orchestration code can be unit-tested without a docker/podman
+// daemon available.
+type Fake struct {
+	RuntimeName string
+	IsAvailable bool
+	Output      string
+	Err         error
+
+	Calls []RunConfig
+}
+
+func (f *Fake) Name() string {
+	if f.RuntimeName == "" {
+		return "fake"
+	}
+	return f.RuntimeName
+}
+
+func (f *Fake) Available() bool { return f.IsAvailable }
+
+func (f *Fake) Run(cfg RunConfig) (string, error) {
+	f.Calls = append(f.Calls, cfg)
+	return f.Output, f.Err
+}
@@ -0,0 +1,103 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Podman runs scans via the `podman` CLI, with rootless execution in mind:
+// it keep-id maps the invoking user into the container's user namespace so
+// bind-mounted cache/key files keep their host ownership, and relabels
+// mounts for SELinux hosts where required.
+type Podman struct{}
+
+func (p *Podman) Name() string { return "podman" }
+
+func (p *Podman) Available() bool {
+	_, err := lookPath("podman")
+	return err == nil
+}
+
+func (p *Podman) Run(cfg RunConfig) (string, error) {
+	args := []string{"run", "--rm"}
+
+	if os.Getuid() != 0 {
+		// map the invoking (rootless) user's uid/gid to the same ids
+		// inside the container, so files written to bind mounts are
+		// owned by the host user rather than a subuid-mapped one
+		args = append(args, "--userns=keep-id")
+	}
+
+	if needsSELinuxDisable() {
+		args = append(args, "--security-opt", "label=disable")
+	}
+
+	for _, m := range cfg.Mounts {
+		args = append(args, "-v", podmanVolumeArg(m))
+	}
+	for k, v := range cfg.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	args = append(args, resourceAndExtraArgs(cfg)...)
+
+	args = append(args, cfg.ImageURL)
+	args = append(args, cfg.Command...)
+
+	cmd := exec.Command("podman", args...)
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("CONTAINER_HOST=unix://%s/podman/podman.sock", runtimeDir))
+	}
+
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// podmanVolumeArg renders a Mount as a `-v` argument, appending the ":Z"
+// SELinux relabel flag when requested.
+func podmanVolumeArg(m Mount) string {
+	flags := ""
+	if m.ReadOnly {
+		flags += ":ro"
+	}
+	if m.Relabel {
+		flags += ":Z"
+	}
+	return fmt.Sprintf("%s:%s%s", m.Source, m.Target, flags)
+}
+
+// needsSELinuxDisable reports whether SELinux is actually enforcing on this
+// host, i.e. rootless Podman would otherwise deny container access to
+// bind-mounted host paths. /sys/fs/selinux existing is not enough - many
+// distros mount it while running permissive or without a policy loaded, and
+// disabling label confinement there would needlessly weaken it.
+func needsSELinuxDisable() bool {
+	data, err := os.ReadFile("/sys/fs/selinux/enforce")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(data)) == "1"
+}
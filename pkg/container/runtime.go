@@ -0,0 +1,144 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+// Package container abstracts the container engine used to run privado's
+// scan image, so the orchestration code in cmd/pkg/scan does not need to
+// know whether it is talking to Docker or Podman.
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Mount describes a single bind mount into the scan container.
+type Mount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+	// Relabel requests an SELinux ":Z" (private, unshared) relabel of
+	// Source. Docker ignores this; Podman honors it on SELinux hosts.
+	Relabel bool
+}
+
+// RunConfig is the runtime-agnostic description of a single scan container
+// invocation.
+type RunConfig struct {
+	ImageURL string
+	Mounts   []Mount
+	Env      map[string]string
+	Command  []string
+
+	// ExtraMounts are raw `-v`-style mount specs ("src:dst[:opts]") from
+	// the user's own [containers] config, appended verbatim after Mounts.
+	ExtraMounts []string
+	// ExtraEnv are raw "KEY=VALUE" pairs from the user's own [containers]
+	// config, appended after Env.
+	ExtraEnv []string
+	// MemoryLimit and CPULimit are passed straight through as --memory
+	// and --cpus when set (e.g. "2g", "2").
+	MemoryLimit string
+	CPULimit    string
+}
+
+// Runtime is implemented by each supported container engine.
+type Runtime interface {
+	// Name identifies the runtime, e.g. "docker" or "podman".
+	Name() string
+	// Available reports whether the runtime's binary is usable on this host.
+	Available() bool
+	// Run executes cfg under this runtime and returns its combined output.
+	Run(cfg RunConfig) (string, error)
+}
+
+// envRuntimeKey is the environment variable used to force a specific
+// runtime, bypassing auto-detection.
+const envRuntimeKey = "PRIVADO_CONTAINER_RUNTIME"
+
+// Detect picks the container runtime to use, in order of precedence:
+// the PRIVADO_CONTAINER_RUNTIME env var, the preferred value from TOML
+// config (configuredRuntime, may be empty), then auto-detection of
+// whichever of podman/docker is on $PATH (podman preferred, since it
+// supports rootless execution out of the box).
+func Detect(configuredRuntime string) (Runtime, error) {
+	name := os.Getenv(envRuntimeKey)
+	if name == "" {
+		name = configuredRuntime
+	}
+
+	if name != "" {
+		rt, err := newRuntime(name)
+		if err != nil {
+			return nil, err
+		}
+		if !rt.Available() {
+			return nil, fmt.Errorf("container runtime %q was requested but its binary was not found on $PATH", name)
+		}
+		return rt, nil
+	}
+
+	for _, candidate := range []string{"podman", "docker"} {
+		rt, _ := newRuntime(candidate)
+		if rt.Available() {
+			return rt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no container runtime found on $PATH (tried podman, docker)")
+}
+
+func newRuntime(name string) (Runtime, error) {
+	switch name {
+	case "docker":
+		return &Docker{}, nil
+	case "podman":
+		return &Podman{}, nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", name)
+	}
+}
+
+// lookPath is a package-level var so tests can stub it out.
+var lookPath = exec.LookPath
+
+// resourceAndExtraArgs renders the runtime-agnostic parts of RunConfig that
+// both Docker and Podman accept identically: extra user-supplied mounts and
+// env vars, and memory/cpu resource limits.
+func resourceAndExtraArgs(cfg RunConfig) []string {
+	var args []string
+
+	for _, m := range cfg.ExtraMounts {
+		args = append(args, "-v", m)
+	}
+	for _, e := range cfg.ExtraEnv {
+		args = append(args, "-e", e)
+	}
+	if cfg.MemoryLimit != "" {
+		args = append(args, "--memory", cfg.MemoryLimit)
+	}
+	if cfg.CPULimit != "" {
+		args = append(args, "--cpus", cfg.CPULimit)
+	}
+
+	return args
+}
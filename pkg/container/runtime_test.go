@@ -0,0 +1,159 @@
+/**
+ * This file is part of Privado OSS.
+ *
+ * Privado is an open source static code analysis tool to discover data flows in the code.
+ * Copyright (C) 2022 Privado, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ * For more information, contact support@privado.ai
+ */
+
+package container
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubLookPath swaps the package-level lookPath for the duration of a test,
+// restoring the original on cleanup.
+func stubLookPath(t *testing.T, available ...string) {
+	t.Helper()
+	orig := lookPath
+	lookPath = func(file string) (string, error) {
+		for _, name := range available {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("executable file not found in $PATH")
+	}
+	t.Cleanup(func() { lookPath = orig })
+}
+
+func TestDetect_EnvOverrideWinsOverConfigured(t *testing.T) {
+	stubLookPath(t, "docker", "podman")
+	t.Setenv(envRuntimeKey, "docker")
+
+	rt, err := Detect("podman")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rt.Name() != "docker" {
+		t.Fatalf("expected docker, got %s", rt.Name())
+	}
+}
+
+func TestDetect_FallsBackToConfiguredRuntime(t *testing.T) {
+	stubLookPath(t, "podman")
+	t.Setenv(envRuntimeKey, "")
+
+	rt, err := Detect("podman")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rt.Name() != "podman" {
+		t.Fatalf("expected podman, got %s", rt.Name())
+	}
+}
+
+func TestDetect_RequestedRuntimeNotOnPath(t *testing.T) {
+	stubLookPath(t, "docker")
+	t.Setenv(envRuntimeKey, "")
+
+	if _, err := Detect("podman"); err == nil {
+		t.Fatal("expected an error when the configured runtime's binary is missing")
+	}
+}
+
+func TestDetect_AutoDetectPrefersPodman(t *testing.T) {
+	stubLookPath(t, "docker", "podman")
+	t.Setenv(envRuntimeKey, "")
+
+	rt, err := Detect("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rt.Name() != "podman" {
+		t.Fatalf("expected podman to be preferred, got %s", rt.Name())
+	}
+}
+
+func TestDetect_AutoDetectFallsBackToDocker(t *testing.T) {
+	stubLookPath(t, "docker")
+	t.Setenv(envRuntimeKey, "")
+
+	rt, err := Detect("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rt.Name() != "docker" {
+		t.Fatalf("expected docker, got %s", rt.Name())
+	}
+}
+
+func TestDetect_NoRuntimeAvailable(t *testing.T) {
+	stubLookPath(t)
+	t.Setenv(envRuntimeKey, "")
+
+	if _, err := Detect(""); err == nil {
+		t.Fatal("expected an error when neither docker nor podman is on $PATH")
+	}
+}
+
+func TestDockerVolumeArg(t *testing.T) {
+	got := volumeArg(Mount{Source: "/host/m2", Target: "/root/.m2", ReadOnly: true})
+	want := "/host/m2:/root/.m2:ro"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestPodmanVolumeArg_RelabelsForSELinux(t *testing.T) {
+	got := podmanVolumeArg(Mount{Source: "/host/keys", Target: "/app/keys", Relabel: true})
+	want := "/host/keys:/app/keys:Z"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFake_RecordsRunCalls(t *testing.T) {
+	fake := &Fake{IsAvailable: true, Output: "scan complete"}
+	cfg := RunConfig{ImageURL: "privado-patched:latest", Command: []string{"scan", "/app/code"}}
+
+	out, err := fake.Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "scan complete" {
+		t.Fatalf("got output %q, want %q", out, "scan complete")
+	}
+
+	if len(fake.Calls) != 1 {
+		t.Fatalf("expected 1 recorded call, got %d", len(fake.Calls))
+	}
+	if fake.Calls[0].ImageURL != cfg.ImageURL {
+		t.Fatalf("recorded call has ImageURL %q, want %q", fake.Calls[0].ImageURL, cfg.ImageURL)
+	}
+}
+
+func TestFake_PropagatesConfiguredError(t *testing.T) {
+	wantErr := errors.New("container exited 1")
+	fake := &Fake{IsAvailable: true, Err: wantErr}
+
+	if _, err := fake.Run(RunConfig{}); err != wantErr {
+		t.Fatalf("got error %v, want %v", err, wantErr)
+	}
+}